@@ -2,202 +2,137 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"go.opentelemetry.io/contrib/bridges/otelslog"
-	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
-	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
-	"go.opentelemetry.io/otel/sdk/log"
-	"go.opentelemetry.io/otel/sdk/resource"
-	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
-	"io"
-	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
 	"log/slog"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
+
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/auditfilter"
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/auditsink"
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/exporter"
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/filesource"
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/otelbridge"
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/tracebridge"
 )
 
 func main() {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Create resource.
-	res, err := newResource()
+	res, err := otelbridge.NewResource()
+	if err != nil {
+		panic(err)
+	}
+
+	// Build the configured exporters (AUDIT_EXPORTERS, etc.) and register
+	// every one of them on the logger provider so events fan out to all
+	// of them at once.
+	processors, err := exporter.BuildProcessors(ctx, exporter.ConfigFromEnv())
 	if err != nil {
 		panic(err)
 	}
 
 	// Create a logger provider.
 	// You can pass this instance directly when creating bridges.
-	loggerProvider, err := newLoggerProvider(res)
+	loggerProvider, err := otelbridge.NewLoggerProvider(res, processors...)
 	if err != nil {
 		panic(err)
 	}
 
 	// Handle shutdown properly so nothing leaks.
 	defer func() {
-		if err := loggerProvider.Shutdown(ctx); err != nil {
+		if err := loggerProvider.Shutdown(context.Background()); err != nil {
 			fmt.Println(err)
 		}
 	}()
 
-	logger := slog.New(newOtelLogHandler(loggerProvider))
-
-	event, err := readAuditEvent("audit.log")
+	logger := slog.New(otelbridge.NewLogHandler(loggerProvider))
 
-	logger.LogAttrs(ctx, 8, "Hello World!", getAttributesFromAuditEvent(event)...)
-
-}
+	// AUDIT_FILTER_RULES / AUDIT_FILTER_CONFIG_FILE opt into redacting
+	// Secrets/PII out of audit events before they reach any exporter. Nil
+	// when unset, which means events are forwarded unredacted.
+	filter, err := auditfilter.ConfigFromEnv()
+	if err != nil {
+		panic(err)
+	}
 
-func newOtelLogHandler(loggerProvider *log.LoggerProvider) slog.Handler {
-	return otelslog.NewHandler("test", otelslog.WithLoggerProvider(loggerProvider))
-}
+	// AUDIT_TRACE_CORRELATION opts into correlating audit events into OTel
+	// traces via tracebridge, keyed on AuditID. Off by default since it
+	// requires its own trace pipeline alongside the log one.
+	var traceBridge *tracebridge.Bridge
+	if os.Getenv("AUDIT_TRACE_CORRELATION") == "true" {
+		tracerProvider, err := tracebridge.NewTracerProvider(res, os.Getenv("AUDIT_TRACE_ENDPOINT"), os.Getenv("AUDIT_TRACE_INSECURE") == "true")
+		if err != nil {
+			panic(err)
+		}
+		defer func() {
+			if err := tracerProvider.Shutdown(context.Background()); err != nil {
+				fmt.Println(err)
+			}
+		}()
+		traceBridge = tracebridge.New(tracerProvider)
+	}
 
-func getAttributesFromAuditEvent(event *auditapi.Event) []slog.Attr {
-	attrs := []slog.Attr{
-		{
-			Key:   "audit.level",
-			Value: slog.AnyValue(event.Level),
-		},
-		{
-			Key:   "audit.auditID",
-			Value: slog.AnyValue(event.AuditID),
-		},
-		{
-			Key:   "audit.stage",
-			Value: slog.AnyValue(event.Stage),
-		},
-		{
-			Key:   "audit.requestURI",
-			Value: slog.AnyValue(event.RequestURI),
-		},
-		{
-			Key:   "audit.verb",
-			Value: slog.AnyValue(event.Verb),
-		},
-
-		{
-			Key:   "audit.user.username",
-			Value: slog.AnyValue(event.User.Username),
-		},
-		{
-			Key:   "audit.user.uid",
-			Value: slog.AnyValue(event.User.UID),
-		},
-		{
-			Key:   "audit.user.groups",
-			Value: slog.AnyValue(strings.Join(event.User.Groups, ",")),
-		},
-
-		{
-			Key:   "audit.sourceIPs",
-			Value: slog.AnyValue(strings.Join(event.SourceIPs, ",")),
-		},
-		{
-			Key:   "audit.userAgent",
-			Value: slog.AnyValue(event.UserAgent),
-		},
-
-		{
-			Key:   "audit.objectRef.uid",
-			Value: slog.AnyValue(event.ObjectRef.UID),
-		},
-		{
-			Key:   "audit.objectRef.resource",
-			Value: slog.AnyValue(event.ObjectRef.Resource),
-		},
-		{
-			Key:   "audit.objectRef.name",
-			Value: slog.AnyValue(event.ObjectRef.Name),
-		},
-		{
-			Key:   "audit.objectRef.namespace",
-			Value: slog.AnyValue(event.ObjectRef.Namespace),
-		},
-		{
-			Key:   "audit.objectRef.apiGroup",
-			Value: slog.AnyValue(event.ObjectRef.APIGroup),
-		},
-
-		{
-			Key:   "audit.objectRef.apiVersion",
-			Value: slog.AnyValue(event.ObjectRef.APIVersion),
-		},
-		{
-			Key:   "audit.objectRef.resourceVersion",
-			Value: slog.AnyValue(event.ObjectRef.ResourceVersion),
-		},
-
-		{
-			Key:   "audit.requestObject",
-			Value: slog.AnyValue(event.RequestObject),
-		},
-		{
-			Key:   "audit.responseObject",
-			Value: slog.AnyValue(event.ResponseObject),
-		},
-		{
-			Key:   "audit.responseStatus",
-			Value: slog.AnyValue(event.ResponseStatus),
-		},
-		{
-			Key:   "audit.requestReceivedTimestamp",
-			Value: slog.AnyValue(event.RequestReceivedTimestamp),
-		},
-		{
-			Key:   "audit.stageTimestamp",
-			Value: slog.AnyValue(event.StageTimestamp),
-		},
+	// AUDIT_SOURCE selects how audit events reach this process: "webhook"
+	// (default) runs the apiserver audit webhook backend; "file" tails the
+	// NDJSON log apiserver writes with --audit-log-path, for clusters that
+	// already ship audit logs to disk.
+	source := os.Getenv("AUDIT_SOURCE")
+	if source == "" {
+		source = "webhook"
 	}
-	return attrs
-}
 
-func readAuditEvent(filePath string) (*auditapi.Event, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+	switch source {
+	case "webhook":
+		runWebhook(ctx, logger, filter, traceBridge)
+	case "file":
+		runFileSource(ctx, logger, filter, traceBridge)
+	default:
+		panic(fmt.Sprintf("unknown AUDIT_SOURCE %q", source))
 	}
+}
 
-	defer file.Close()
+func runWebhook(ctx context.Context, logger *slog.Logger, filter *auditfilter.Filter, traceBridge *tracebridge.Bridge) {
+	sink := auditsink.NewSink(logger, filter, traceBridge)
 
-	// Read the JSON file
-	byteValue, err := io.ReadAll(file)
-	if err != nil {
-		return nil, err
+	addr := os.Getenv("AUDIT_WEBHOOK_ADDR")
+	if addr == "" {
+		addr = ":8080"
 	}
-	var event auditapi.Event
-	if err := json.Unmarshal(byteValue, &event); err != nil {
-		return nil, err
-	}
-
-	return &event, nil
-}
 
-func newResource() (*resource.Resource, error) {
-	return resource.Merge(resource.Default(),
-		resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName("test-service"),
-			semconv.ServiceVersion("0.1.0"),
-		))
+	slog.Info("starting audit webhook backend", "addr", addr)
+	if err := auditsink.ListenAndServe(ctx, addr, sink); err != nil {
+		panic(err)
+	}
 }
 
-func newLoggerProvider(res *resource.Resource) (*log.LoggerProvider, error) {
-	exporter, err := getStdoutLogExporter()
-	if err != nil {
-		return nil, err
+func runFileSource(ctx context.Context, logger *slog.Logger, filter *auditfilter.Filter, traceBridge *tracebridge.Bridge) {
+	logPath := os.Getenv("AUDIT_LOG_PATH")
+	if logPath == "" {
+		logPath = "/var/log/kubernetes/audit.log"
+	}
+	checkpointPath := os.Getenv("AUDIT_CHECKPOINT_PATH")
+	if checkpointPath == "" {
+		checkpointPath = logPath + ".checkpoint"
 	}
-	processor := log.NewBatchProcessor(exporter, log.WithMaxQueueSize(4), log.WithExportMaxBatchSize(1))
-	provider := log.NewLoggerProvider(
-		log.WithResource(res),
-		log.WithProcessor(processor),
-	)
-	return provider, nil
-}
 
-func getStdoutLogExporter() (log.Exporter, error) {
-	return stdoutlog.New(stdoutlog.WithPrettyPrint())
-}
+	slog.Info("tailing audit log file", "path", logPath, "checkpoint", checkpointPath)
 
-func getHTTPlogExporter() (log.Exporter, error) {
-	return otlploghttp.New(context.Background())
+	tailer := filesource.NewTailer(logPath, checkpointPath, 0)
+	err := tailer.Run(ctx, func(ctx context.Context, event *auditapi.Event) error {
+		attrs := otelbridge.AttributesFromAuditEvent(event, filter)
+		if traceBridge != nil {
+			attrs = append(attrs, traceBridge.Correlate(ctx, event, tracebridge.ConvertAttrs(attrs))...)
+		}
+		logger.LogAttrs(ctx, slog.LevelInfo, "audit event", attrs...)
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		panic(err)
+	}
 }
@@ -0,0 +1,397 @@
+// Package auditfilter redacts or drops fields from Kubernetes audit events
+// before they are shipped anywhere. Audit events routinely carry Secrets,
+// bearer tokens, and other PII in requestObject/responseObject, so this is
+// meant to run once per event ahead of every exporter.
+package auditfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// Rule identifies a single field to redact or drop from an audit event.
+type Rule struct {
+	// Path is a JSONPath-style expression naming the field, e.g.
+	// "objectRef.namespace", "['requestObject']['spec']", "user.groups[*]",
+	// or "responseObject.items[0].data". Dot notation, bracket notation
+	// with quoted keys, integer list indices, and the [*] wildcard (over
+	// both lists and map keys) are all supported.
+	Path string `json:"path"`
+	// Placeholder replaces the matched value. Ignored when Drop is true.
+	Placeholder string `json:"placeholder,omitempty"`
+	// Drop removes the matched key/element entirely instead of replacing
+	// its value with Placeholder.
+	Drop bool `json:"drop,omitempty"`
+}
+
+// Filter applies a set of redaction Rules to audit events.
+type Filter struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	segments []segment
+}
+
+// New compiles rules into a Filter. It returns an error if any Path is
+// malformed.
+func New(rules []Rule) (*Filter, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, r := range rules {
+		segs, err := parsePath(r.Path)
+		if err != nil {
+			return nil, fmt.Errorf("auditfilter: rule %q: %w", r.Path, err)
+		}
+		compiled = append(compiled, compiledRule{Rule: r, segments: segs})
+	}
+	return &Filter{rules: compiled}, nil
+}
+
+// ConfigFromEnv builds a Filter from operator-supplied rules:
+//
+//	AUDIT_FILTER_RULES        inline JSON array of Rule, e.g.
+//	                          [{"path":"requestObject.data","drop":true}]
+//	AUDIT_FILTER_CONFIG_FILE  path to a file holding the same JSON array;
+//	                          only consulted if AUDIT_FILTER_RULES is unset
+//
+// With neither set, ConfigFromEnv returns a nil *Filter, and callers should
+// treat that as "redact nothing" rather than an error -- filtering is
+// opt-in, since the rules are cluster-specific.
+func ConfigFromEnv() (*Filter, error) {
+	data := os.Getenv("AUDIT_FILTER_RULES")
+	if data == "" {
+		path := os.Getenv("AUDIT_FILTER_CONFIG_FILE")
+		if path == "" {
+			return nil, nil
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("auditfilter: read %s: %w", path, err)
+		}
+		data = string(raw)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal([]byte(data), &rules); err != nil {
+		return nil, fmt.Errorf("auditfilter: decode rules: %w", err)
+	}
+	return New(rules)
+}
+
+// Apply redacts event in place according to f's rules. Rules naming fields
+// that are absent on the event (nil pointers, missing map keys, out of
+// range indices) are silently skipped.
+func (f *Filter) Apply(event *auditapi.Event) error {
+	for _, rule := range f.rules {
+		if err := f.applyRule(event, rule); err != nil {
+			return fmt.Errorf("auditfilter: apply rule %q: %w", rule.Path, err)
+		}
+	}
+	return nil
+}
+
+func (f *Filter) applyRule(event *auditapi.Event, rule compiledRule) error {
+	head := rule.segments[0]
+	switch head.key {
+	case "requestObject":
+		return redactRawObject(event.RequestObject, rule.segments[1:], rule.Placeholder, rule.Drop)
+	case "responseObject":
+		return redactRawObject(event.ResponseObject, rule.segments[1:], rule.Placeholder, rule.Drop)
+	default:
+		return redactField(reflect.ValueOf(event).Elem(), rule.segments, rule.Placeholder, rule.Drop)
+	}
+}
+
+// redactRawObject decodes the embedded JSON of a requestObject/responseObject
+// (carried as runtime.Unknown.Raw), applies the remaining path segments to
+// the decoded tree, and re-encodes it back into Raw.
+func redactRawObject(obj *runtime.Unknown, segments []segment, placeholder string, drop bool) error {
+	if obj == nil || len(obj.Raw) == 0 || len(segments) == 0 {
+		return nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(obj.Raw, &decoded); err != nil {
+		return err
+	}
+	decoded = applyJSONSegments(decoded, segments, placeholder, drop)
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return err
+	}
+	obj.Raw = encoded
+	return nil
+}
+
+func applyJSONSegments(node interface{}, segments []segment, placeholder string, drop bool) interface{} {
+	seg := segments[0]
+	rest := segments[1:]
+
+	switch seg.kind {
+	case segKey:
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return node
+		}
+		val, exists := m[seg.key]
+		if !exists {
+			return node
+		}
+		if len(rest) == 0 {
+			if drop {
+				delete(m, seg.key)
+			} else {
+				m[seg.key] = placeholder
+			}
+			return node
+		}
+		m[seg.key] = applyJSONSegments(val, rest, placeholder, drop)
+		return node
+
+	case segIndex:
+		s, ok := node.([]interface{})
+		if !ok || seg.index < 0 || seg.index >= len(s) {
+			return node
+		}
+		if len(rest) == 0 {
+			if drop {
+				return append(s[:seg.index:seg.index], s[seg.index+1:]...)
+			}
+			s[seg.index] = placeholder
+			return s
+		}
+		s[seg.index] = applyJSONSegments(s[seg.index], rest, placeholder, drop)
+		return s
+
+	case segWildcard:
+		switch n := node.(type) {
+		case []interface{}:
+			if len(rest) == 0 {
+				if drop {
+					return []interface{}{}
+				}
+				for i := range n {
+					n[i] = placeholder
+				}
+				return n
+			}
+			for i := range n {
+				n[i] = applyJSONSegments(n[i], rest, placeholder, drop)
+			}
+			return n
+		case map[string]interface{}:
+			if len(rest) == 0 {
+				if drop {
+					for k := range n {
+						delete(n, k)
+					}
+				} else {
+					for k := range n {
+						n[k] = placeholder
+					}
+				}
+				return n
+			}
+			for k, v := range n {
+				n[k] = applyJSONSegments(v, rest, placeholder, drop)
+			}
+			return n
+		}
+	}
+	return node
+}
+
+// redactField walks the typed Event struct via reflection, following
+// pointers, maps, and slices, and applies the placeholder/drop behavior at
+// the final segment.
+func redactField(v reflect.Value, segments []segment, placeholder string, drop bool) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	seg := segments[0]
+	rest := segments[1:]
+
+	switch seg.kind {
+	case segKey:
+		switch v.Kind() {
+		case reflect.Struct:
+			fv, ok := fieldByJSONName(v, seg.key)
+			if !ok {
+				return nil
+			}
+			if len(rest) == 0 {
+				return setOrClear(fv, placeholder, drop)
+			}
+			return redactField(fv, rest, placeholder, drop)
+		case reflect.Map:
+			return redactMapKey(v, seg.key, rest, placeholder, drop)
+		}
+		return nil
+
+	case segIndex:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil
+		}
+		if seg.index < 0 || seg.index >= v.Len() {
+			return nil
+		}
+		elem := v.Index(seg.index)
+		if len(rest) == 0 {
+			return setOrClear(elem, placeholder, drop)
+		}
+		return redactField(elem, rest, placeholder, drop)
+
+	case segWildcard:
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				elem := v.Index(i)
+				if len(rest) == 0 {
+					if err := setOrClear(elem, placeholder, drop); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := redactField(elem, rest, placeholder, drop); err != nil {
+					return err
+				}
+			}
+			return nil
+		case reflect.Map:
+			for _, key := range v.MapKeys() {
+				if err := redactMapKey(v, fmt.Sprint(key.Interface()), rest, placeholder, drop); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// redactMapKey redacts a single key of a map value. Map values are not
+// addressable, so the updated value is copied back with SetMapIndex.
+func redactMapKey(m reflect.Value, key string, rest []segment, placeholder string, drop bool) error {
+	mapKey := reflect.ValueOf(key).Convert(m.Type().Key())
+	val := m.MapIndex(mapKey)
+	if !val.IsValid() {
+		return nil
+	}
+	if drop && len(rest) == 0 {
+		m.SetMapIndex(mapKey, reflect.Value{})
+		return nil
+	}
+	nv := reflect.New(val.Type()).Elem()
+	nv.Set(val)
+	var err error
+	if len(rest) == 0 {
+		err = setOrClear(nv, placeholder, drop)
+	} else {
+		err = redactField(nv, rest, placeholder, drop)
+	}
+	if err != nil {
+		return err
+	}
+	m.SetMapIndex(mapKey, nv)
+	return nil
+}
+
+func setOrClear(v reflect.Value, placeholder string, drop bool) error {
+	if !v.CanSet() {
+		return nil
+	}
+	if drop {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(placeholder)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(placeholder))
+	default:
+		v.Set(reflect.Zero(v.Type()))
+	}
+	return nil
+}
+
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tagName := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tagName == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+type segKind int
+
+const (
+	segKey segKind = iota
+	segIndex
+	segWildcard
+)
+
+type segment struct {
+	kind  segKind
+	key   string
+	index int
+}
+
+func parsePath(path string) ([]segment, error) {
+	var segs []segment
+	i, n := 0, len(path)
+	for i < n {
+		switch {
+		case path[i] == '.':
+			i++
+		case path[i] == '[':
+			j := strings.IndexByte(path[i:], ']')
+			if j < 0 {
+				return nil, fmt.Errorf("unterminated '[' in path %q", path)
+			}
+			inner := strings.TrimSpace(path[i+1 : i+j])
+			i += j + 1
+			switch {
+			case inner == "*":
+				segs = append(segs, segment{kind: segWildcard})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segs = append(segs, segment{kind: segKey, key: inner[1 : len(inner)-1]})
+			default:
+				idx, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in path %q", inner, path)
+				}
+				segs = append(segs, segment{kind: segIndex, index: idx})
+			}
+		default:
+			j := i
+			for j < n && path[j] != '.' && path[j] != '[' {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("empty path segment in %q", path)
+			}
+			segs = append(segs, segment{kind: segKey, key: path[i:j]})
+			i = j
+		}
+	}
+	if len(segs) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return segs, nil
+}
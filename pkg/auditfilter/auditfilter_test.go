@@ -0,0 +1,206 @@
+package auditfilter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func TestFilter_RedactsTypedField(t *testing.T) {
+	event := &auditapi.Event{
+		User: authnv1.UserInfo{Username: "alice", Groups: []string{"system:authenticated"}},
+	}
+
+	f, err := New([]Rule{{Path: "user.username", Placeholder: "REDACTED"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := f.Apply(event); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if event.User.Username != "REDACTED" {
+		t.Fatalf("username = %q, want REDACTED", event.User.Username)
+	}
+}
+
+func TestFilter_DropsTypedFieldViaWildcard(t *testing.T) {
+	event := &auditapi.Event{
+		User: authnv1.UserInfo{Username: "alice", Groups: []string{"a", "b"}},
+	}
+
+	f, err := New([]Rule{{Path: "user.groups[*]", Drop: true}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := f.Apply(event); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	for i, g := range event.User.Groups {
+		if g != "" {
+			t.Errorf("groups[%d] = %q, want zeroed", i, g)
+		}
+	}
+}
+
+func TestFilter_RedactsRawRequestObject(t *testing.T) {
+	raw, err := json.Marshal(map[string]interface{}{
+		"kind": "Secret",
+		"data": map[string]interface{}{"password": "hunter2"},
+	})
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	event := &auditapi.Event{
+		RequestObject: &runtime.Unknown{Raw: raw},
+	}
+
+	f, err := New([]Rule{{Path: "requestObject.data", Placeholder: "REDACTED"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := f.Apply(event); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(event.RequestObject.Raw, &decoded); err != nil {
+		t.Fatalf("decode redacted raw: %v", err)
+	}
+	if decoded["data"] != "REDACTED" {
+		t.Fatalf("data = %v, want REDACTED", decoded["data"])
+	}
+	if decoded["kind"] != "Secret" {
+		t.Fatalf("kind = %v, want untouched", decoded["kind"])
+	}
+}
+
+func TestFilter_DropsRawResponseObjectKey(t *testing.T) {
+	raw, err := json.Marshal(map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"data": "s3cr3t"},
+			map[string]interface{}{"data": "also-s3cr3t"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	event := &auditapi.Event{
+		ResponseObject: &runtime.Unknown{Raw: raw},
+	}
+
+	f, err := New([]Rule{{Path: "responseObject.items[*].data", Drop: true}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := f.Apply(event); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	var decoded struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(event.ResponseObject.Raw, &decoded); err != nil {
+		t.Fatalf("decode redacted raw: %v", err)
+	}
+	for i, item := range decoded.Items {
+		if _, ok := item["data"]; ok {
+			t.Errorf("items[%d] still has data key, want dropped", i)
+		}
+	}
+}
+
+func TestFilter_MissingFieldIsNoop(t *testing.T) {
+	event := &auditapi.Event{}
+
+	f, err := New([]Rule{{Path: "objectRef.namespace", Placeholder: "REDACTED"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := f.Apply(event); err != nil {
+		t.Fatalf("Apply on event with nil ObjectRef: %v", err)
+	}
+}
+
+func TestNew_InvalidPath(t *testing.T) {
+	if _, err := New([]Rule{{Path: "user['groups"}}); err == nil {
+		t.Fatal("expected error for unterminated '['")
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	segs, err := parsePath(`objectRef['namespace'].items[2][*]`)
+	if err != nil {
+		t.Fatalf("parsePath: %v", err)
+	}
+	want := []segment{
+		{kind: segKey, key: "objectRef"},
+		{kind: segKey, key: "namespace"},
+		{kind: segKey, key: "items"},
+		{kind: segIndex, index: 2},
+		{kind: segWildcard},
+	}
+	if len(segs) != len(want) {
+		t.Fatalf("got %d segments, want %d: %+v", len(segs), len(want), segs)
+	}
+	for i, s := range segs {
+		if s != want[i] {
+			t.Errorf("segment %d = %+v, want %+v", i, s, want[i])
+		}
+	}
+}
+
+func TestConfigFromEnv_Unset(t *testing.T) {
+	t.Setenv("AUDIT_FILTER_RULES", "")
+	t.Setenv("AUDIT_FILTER_CONFIG_FILE", "")
+
+	f, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	if f != nil {
+		t.Fatalf("filter = %v, want nil when unset", f)
+	}
+}
+
+func TestConfigFromEnv_InlineRules(t *testing.T) {
+	t.Setenv("AUDIT_FILTER_RULES", `[{"path":"user.username","placeholder":"REDACTED"}]`)
+	t.Setenv("AUDIT_FILTER_CONFIG_FILE", "")
+
+	f, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	event := &auditapi.Event{User: authnv1.UserInfo{Username: "alice"}}
+	if err := f.Apply(event); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if event.User.Username != "REDACTED" {
+		t.Fatalf("username = %q, want REDACTED", event.User.Username)
+	}
+}
+
+func TestConfigFromEnv_ConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`[{"path":"user.username","drop":true}]`), 0o600); err != nil {
+		t.Fatalf("write rules file: %v", err)
+	}
+	t.Setenv("AUDIT_FILTER_RULES", "")
+	t.Setenv("AUDIT_FILTER_CONFIG_FILE", path)
+
+	f, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	event := &auditapi.Event{User: authnv1.UserInfo{Username: "alice"}}
+	if err := f.Apply(event); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if event.User.Username != "" {
+		t.Fatalf("username = %q, want dropped", event.User.Username)
+	}
+}
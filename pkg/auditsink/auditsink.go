@@ -0,0 +1,121 @@
+// Package auditsink implements the Kubernetes dynamic audit webhook backend
+// contract: an HTTP endpoint that the apiserver POSTs batches of audit
+// events to (see --audit-webhook-config-file and k8s.io/apiserver/pkg/audit).
+package auditsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/auditfilter"
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/otelbridge"
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/tracebridge"
+)
+
+// Sink is an http.Handler that accepts audit.k8s.io/v1 EventList bodies and
+// forwards every Event it contains through the OTel log bridge.
+type Sink struct {
+	logger *slog.Logger
+	filter *auditfilter.Filter
+	trace  *tracebridge.Bridge
+}
+
+// NewSink returns a Sink that logs every received event through logger.
+// filter may be nil, in which case events are forwarded unredacted. trace
+// may be nil, in which case events are not correlated into OTel traces.
+func NewSink(logger *slog.Logger, filter *auditfilter.Filter, trace *tracebridge.Bridge) *Sink {
+	return &Sink{logger: logger, filter: filter, trace: trace}
+}
+
+// MaxBodyBytes caps a single EventList payload. apiserver batches audit
+// events into one POST per --audit-webhook-batch-max-size, so this is sized
+// well above any realistic batch while still bounding how much an
+// unbounded or malicious body can cost us.
+const MaxBodyBytes = 64 << 20 // 64MiB
+
+// ServeHTTP implements the webhook backend contract: apiserver POSTs an
+// EventList and expects a 2xx response on success. Returning a non-2xx
+// status causes apiserver to retry the batch, so partial failures are
+// logged rather than aborting the loop.
+func (s *Sink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBodyBytes)
+
+	var list auditapi.EventList
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		http.Error(w, fmt.Sprintf("decode event list: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	for i := range list.Items {
+		event := &list.Items[i]
+		attrs := otelbridge.AttributesFromAuditEvent(event, s.filter)
+		if s.trace != nil {
+			attrs = append(attrs, s.trace.Correlate(ctx, event, tracebridge.ConvertAttrs(attrs))...)
+		}
+		s.logger.LogAttrs(ctx, slog.LevelInfo, "audit event", attrs...)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ShutdownTimeout bounds how long ListenAndServe waits for in-flight
+// requests to finish once ctx is canceled before giving up.
+const ShutdownTimeout = 10 * time.Second
+
+// Server timeouts. This endpoint is network-facing -- real clusters point
+// --audit-webhook-config-file at it -- so it needs guards against
+// slow-loris style connection exhaustion from a slow or malicious caller.
+const (
+	ReadHeaderTimeout = 5 * time.Second
+	ReadTimeout       = 30 * time.Second
+	WriteTimeout      = 30 * time.Second
+	IdleTimeout       = 60 * time.Second
+)
+
+// ListenAndServe starts the webhook backend on addr, and shuts it down
+// gracefully when ctx is canceled (e.g. on SIGTERM), so requests in flight
+// get a chance to finish and any audit events they produced can still reach
+// the log pipeline before the process exits.
+func ListenAndServe(ctx context.Context, addr string, sink *Sink) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           sink,
+		ReadHeaderTimeout: ReadHeaderTimeout,
+		ReadTimeout:       ReadTimeout,
+		WriteTimeout:      WriteTimeout,
+		IdleTimeout:       IdleTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("auditsink: shutdown: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package auditsink
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestListenAndServe_ShutsDownOnContextCancel guards against ListenAndServe
+// blocking forever on SIGTERM-driven context cancellation (it used to run
+// under plain http.ListenAndServe, which has no way to stop).
+func TestListenAndServe_ShutsDownOnContextCancel(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sink := NewSink(logger, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ListenAndServe(ctx, "127.0.0.1:0", sink)
+	}()
+
+	// Give the server a moment to start listening, then cancel as if a
+	// SIGTERM had fired.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ListenAndServe returned error on shutdown: %v", err)
+		}
+	case <-time.After(ShutdownTimeout + time.Second):
+		t.Fatal("ListenAndServe did not return after context cancellation")
+	}
+}
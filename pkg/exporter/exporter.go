@@ -0,0 +1,248 @@
+// Package exporter builds OTel log exporters/processors for the audit
+// pipeline from runtime configuration, so a single audit event can be
+// fanned out to any combination of stdout, OTLP/HTTP, and OTLP/gRPC at
+// once.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/sdk/log"
+)
+
+// Defaults sized for a busy cluster. apiserver can emit on the order of
+// hundreds of audit events per second; the previous hard-coded
+// MaxQueueSize=4/ExportMaxBatchSize=1 drops events under any realistic
+// load, so give the queue real headroom and batch aggressively.
+const (
+	DefaultMaxQueueSize       = 2048
+	DefaultExportMaxBatchSize = 512
+	DefaultExportInterval     = time.Second
+	DefaultExportTimeout      = 30 * time.Second
+)
+
+// BatchConfig controls the log.BatchProcessor sizing applied to every
+// exporter this package builds.
+type BatchConfig struct {
+	MaxQueueSize       int
+	ExportMaxBatchSize int
+	ExportInterval     time.Duration
+	ExportTimeout      time.Duration
+}
+
+// DefaultBatchConfig returns the sizing recommended for a busy cluster.
+func DefaultBatchConfig() BatchConfig {
+	return BatchConfig{
+		MaxQueueSize:       DefaultMaxQueueSize,
+		ExportMaxBatchSize: DefaultExportMaxBatchSize,
+		ExportInterval:     DefaultExportInterval,
+		ExportTimeout:      DefaultExportTimeout,
+	}
+}
+
+func (c BatchConfig) options() []log.BatchProcessorOption {
+	return []log.BatchProcessorOption{
+		log.WithMaxQueueSize(c.MaxQueueSize),
+		log.WithExportMaxBatchSize(c.ExportMaxBatchSize),
+		log.WithExportInterval(c.ExportInterval),
+		log.WithExportTimeout(c.ExportTimeout),
+	}
+}
+
+// OTLPConfig configures a single OTLP log exporter, HTTP or gRPC.
+type OTLPConfig struct {
+	Endpoint    string
+	Insecure    bool
+	Headers     map[string]string
+	Compression string // "gzip", or "" for none
+	Timeout     time.Duration
+}
+
+// Config selects which exporters to build and how to size their batch
+// processors. The zero value builds nothing — use ConfigFromEnv to pick up
+// operator-supplied settings.
+type Config struct {
+	Stdout   bool
+	OTLPHTTP *OTLPConfig
+	OTLPGRPC *OTLPConfig
+	Batch    BatchConfig
+}
+
+// ConfigFromEnv builds a Config from environment variables:
+//
+//	AUDIT_EXPORTERS                    comma-separated: stdout,otlphttp,otlpgrpc (default "stdout")
+//	AUDIT_OTLP_ENDPOINT                endpoint shared by otlphttp/otlpgrpc unless overridden below
+//	AUDIT_OTLP_HTTP_ENDPOINT           endpoint override for otlphttp
+//	AUDIT_OTLP_GRPC_ENDPOINT           endpoint override for otlpgrpc
+//	AUDIT_OTLP_INSECURE                "true" to disable TLS on OTLP exporters
+//	AUDIT_OTLP_COMPRESSION             "gzip" or "" (none)
+//	AUDIT_OTLP_HEADERS                 comma-separated key=value pairs, sent on every OTLP export
+//	AUDIT_OTLP_TIMEOUT                 time.ParseDuration string, e.g. "10s"
+//	AUDIT_BATCH_MAX_QUEUE_SIZE         overrides DefaultMaxQueueSize
+//	AUDIT_BATCH_MAX_EXPORT_BATCH_SIZE  overrides DefaultExportMaxBatchSize
+//
+// Retry policy and config-file input are not supported; tune those by
+// pointing AUDIT_OTLP_ENDPOINT at a collector that applies its own retry
+// policy.
+func ConfigFromEnv() Config {
+	cfg := Config{Batch: DefaultBatchConfig()}
+
+	names := os.Getenv("AUDIT_EXPORTERS")
+	if names == "" {
+		names = "stdout"
+	}
+
+	insecure := os.Getenv("AUDIT_OTLP_INSECURE") == "true"
+	compression := os.Getenv("AUDIT_OTLP_COMPRESSION")
+	sharedEndpoint := os.Getenv("AUDIT_OTLP_ENDPOINT")
+	headers := headersFromEnv(os.Getenv("AUDIT_OTLP_HEADERS"))
+	timeout := durationFromEnv(os.Getenv("AUDIT_OTLP_TIMEOUT"))
+
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "stdout":
+			cfg.Stdout = true
+		case "otlphttp":
+			endpoint := firstNonEmpty(os.Getenv("AUDIT_OTLP_HTTP_ENDPOINT"), sharedEndpoint)
+			cfg.OTLPHTTP = &OTLPConfig{Endpoint: endpoint, Insecure: insecure, Headers: headers, Compression: compression, Timeout: timeout}
+		case "otlpgrpc":
+			endpoint := firstNonEmpty(os.Getenv("AUDIT_OTLP_GRPC_ENDPOINT"), sharedEndpoint)
+			cfg.OTLPGRPC = &OTLPConfig{Endpoint: endpoint, Insecure: insecure, Headers: headers, Compression: compression, Timeout: timeout}
+		}
+	}
+
+	if v := os.Getenv("AUDIT_BATCH_MAX_QUEUE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Batch.MaxQueueSize = n
+		}
+	}
+	if v := os.Getenv("AUDIT_BATCH_MAX_EXPORT_BATCH_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Batch.ExportMaxBatchSize = n
+		}
+	}
+
+	return cfg
+}
+
+// headersFromEnv parses a comma-separated list of key=value pairs, as
+// accepted by AUDIT_OTLP_HEADERS. Malformed entries (missing "=") are
+// skipped rather than failing the whole config.
+func headersFromEnv(v string) map[string]string {
+	if v == "" {
+		return nil
+	}
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		headers[key] = value
+	}
+	return headers
+}
+
+// durationFromEnv parses AUDIT_OTLP_TIMEOUT, returning zero (the exporter
+// default) if unset or malformed.
+func durationFromEnv(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// BuildProcessors builds one log.Processor per exporter enabled in cfg.
+// Registering all of them on the same LoggerProvider makes every audit
+// event fan out to each destination simultaneously, e.g. a remote
+// collector alongside a stdout mirror for debugging.
+func BuildProcessors(ctx context.Context, cfg Config) ([]log.Processor, error) {
+	var processors []log.Processor
+
+	if cfg.Stdout {
+		exp, err := stdoutlog.New(stdoutlog.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("exporter: build stdout exporter: %w", err)
+		}
+		processors = append(processors, log.NewBatchProcessor(exp, cfg.Batch.options()...))
+	}
+
+	if cfg.OTLPHTTP != nil {
+		exp, err := newHTTPExporter(ctx, cfg.OTLPHTTP)
+		if err != nil {
+			return nil, fmt.Errorf("exporter: build otlphttp exporter: %w", err)
+		}
+		processors = append(processors, log.NewBatchProcessor(exp, cfg.Batch.options()...))
+	}
+
+	if cfg.OTLPGRPC != nil {
+		exp, err := newGRPCExporter(ctx, cfg.OTLPGRPC)
+		if err != nil {
+			return nil, fmt.Errorf("exporter: build otlpgrpc exporter: %w", err)
+		}
+		processors = append(processors, log.NewBatchProcessor(exp, cfg.Batch.options()...))
+	}
+
+	return processors, nil
+}
+
+func newHTTPExporter(ctx context.Context, cfg *OTLPConfig) (log.Exporter, error) {
+	var opts []otlploghttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlploghttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+	if cfg.Compression == "gzip" {
+		opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlploghttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlploghttp.WithTimeout(cfg.Timeout))
+	}
+	return otlploghttp.New(ctx, opts...)
+}
+
+func newGRPCExporter(ctx context.Context, cfg *OTLPConfig) (log.Exporter, error) {
+	var opts []otlploggrpc.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlploggrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	if cfg.Compression == "gzip" {
+		opts = append(opts, otlploggrpc.WithCompressor("gzip"))
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlploggrpc.WithHeaders(cfg.Headers))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlploggrpc.WithTimeout(cfg.Timeout))
+	}
+	return otlploggrpc.New(ctx, opts...)
+}
@@ -0,0 +1,59 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv_HeadersAndTimeout(t *testing.T) {
+	t.Setenv("AUDIT_EXPORTERS", "otlphttp,otlpgrpc")
+	t.Setenv("AUDIT_OTLP_ENDPOINT", "collector:4317")
+	t.Setenv("AUDIT_OTLP_HEADERS", "x-api-key=secret, x-tenant=team-a")
+	t.Setenv("AUDIT_OTLP_TIMEOUT", "10s")
+
+	cfg := ConfigFromEnv()
+
+	for _, otlp := range []*OTLPConfig{cfg.OTLPHTTP, cfg.OTLPGRPC} {
+		if otlp == nil {
+			t.Fatal("expected both OTLPHTTP and OTLPGRPC to be configured")
+		}
+		if otlp.Timeout != 10*time.Second {
+			t.Errorf("Timeout = %v, want 10s", otlp.Timeout)
+		}
+		if got := otlp.Headers["x-api-key"]; got != "secret" {
+			t.Errorf("Headers[x-api-key] = %q, want %q", got, "secret")
+		}
+		if got := otlp.Headers["x-tenant"]; got != "team-a" {
+			t.Errorf("Headers[x-tenant] = %q, want %q", got, "team-a")
+		}
+	}
+}
+
+func TestConfigFromEnv_NoHeadersOrTimeout(t *testing.T) {
+	t.Setenv("AUDIT_EXPORTERS", "otlphttp")
+	t.Setenv("AUDIT_OTLP_ENDPOINT", "collector:4317")
+	t.Setenv("AUDIT_OTLP_HEADERS", "")
+	t.Setenv("AUDIT_OTLP_TIMEOUT", "")
+
+	cfg := ConfigFromEnv()
+
+	if cfg.OTLPHTTP.Headers != nil {
+		t.Errorf("Headers = %v, want nil", cfg.OTLPHTTP.Headers)
+	}
+	if cfg.OTLPHTTP.Timeout != 0 {
+		t.Errorf("Timeout = %v, want 0", cfg.OTLPHTTP.Timeout)
+	}
+}
+
+func TestConfigFromEnv_MalformedTimeoutIgnored(t *testing.T) {
+	t.Setenv("AUDIT_EXPORTERS", "otlphttp")
+	t.Setenv("AUDIT_OTLP_ENDPOINT", "collector:4317")
+	t.Setenv("AUDIT_OTLP_HEADERS", "")
+	t.Setenv("AUDIT_OTLP_TIMEOUT", "not-a-duration")
+
+	cfg := ConfigFromEnv()
+
+	if cfg.OTLPHTTP.Timeout != 0 {
+		t.Errorf("Timeout = %v, want 0 for malformed input", cfg.OTLPHTTP.Timeout)
+	}
+}
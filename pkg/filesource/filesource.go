@@ -0,0 +1,227 @@
+// Package filesource tails a JSON-lines Kubernetes audit log — the format
+// produced when apiserver is run with --audit-log-path — decoding each
+// line into an audit Event. It follows the file across log rotations and
+// persists a byte-offset checkpoint so restarts resume without duplicating
+// or losing events.
+package filesource
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// EventHandler processes a single decoded audit event.
+type EventHandler func(ctx context.Context, event *auditapi.Event) error
+
+// Tailer tails LogPath, calling an EventHandler for every audit event it
+// decodes, and persists its progress to CheckpointPath.
+type Tailer struct {
+	LogPath        string
+	CheckpointPath string
+	PollInterval   time.Duration
+}
+
+// NewTailer returns a Tailer for logPath, persisting its checkpoint to
+// checkpointPath. pollInterval controls how often the file is checked for
+// new data and rotation; zero selects a 1s default.
+func NewTailer(logPath, checkpointPath string, pollInterval time.Duration) *Tailer {
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	return &Tailer{LogPath: logPath, CheckpointPath: checkpointPath, PollInterval: pollInterval}
+}
+
+// Run tails LogPath until ctx is canceled, calling handle for every decoded
+// event. It follows rotation by inode change (logrotate's typical
+// create-and-swap) as well as in-place truncation, resuming each new
+// generation from offset 0.
+func (t *Tailer) Run(ctx context.Context, handle EventHandler) error {
+	offset, inode, err := t.loadCheckpoint()
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		file, err := t.openForInode(inode, &offset)
+		if err != nil {
+			return err
+		}
+
+		rotated, runErr := t.tailGeneration(ctx, file, &offset, handle)
+		file.Close()
+		if runErr != nil {
+			return runErr
+		}
+		if !rotated {
+			return nil
+		}
+		offset, inode = 0, 0
+	}
+}
+
+// tailGeneration reads file until ctx is canceled or the underlying log
+// rotates out from under it, returning (true, nil) in the latter case so
+// Run can reopen LogPath from scratch.
+func (t *Tailer) tailGeneration(ctx context.Context, file *os.File, offset *int64, handle EventHandler) (rotated bool, err error) {
+	reader := bufio.NewReader(file)
+	ticker := time.NewTicker(t.PollInterval)
+	defer ticker.Stop()
+
+	// pending holds a line fragment read up to EOF without its trailing
+	// newline yet — normal when polling a file the writer is still
+	// appending to. It is only ever merged into a later, complete line;
+	// treating it as a line on its own would drop the event and feed the
+	// rest of the record to processLine as if it were a new one.
+	var pending strings.Builder
+
+	for {
+		for {
+			chunk, readErr := reader.ReadString('\n')
+			if readErr != nil && readErr != io.EOF {
+				return false, fmt.Errorf("filesource: read %s: %w", t.LogPath, readErr)
+			}
+
+			if readErr == io.EOF {
+				pending.WriteString(chunk)
+				break
+			}
+
+			line := chunk
+			if pending.Len() > 0 {
+				line = pending.String() + chunk
+				pending.Reset()
+			}
+
+			*offset += int64(len(line))
+			if perr := t.processLine(ctx, line, handle); perr != nil {
+				slog.Error("filesource: failed to process audit log line", "err", perr)
+			}
+			if cerr := t.saveCheckpoint(*offset, inodeOf(fileInfoOrNil(file))); cerr != nil {
+				slog.Error("filesource: failed to persist checkpoint", "err", cerr)
+			}
+		}
+
+		isRotated, rotErr := t.rotated(file, *offset)
+		if rotErr != nil {
+			return false, rotErr
+		}
+		if isRotated {
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tailer) processLine(ctx context.Context, line string, handle EventHandler) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	var event auditapi.Event
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return fmt.Errorf("filesource: decode audit log line: %w", err)
+	}
+	return handle(ctx, &event)
+}
+
+// rotated reports whether LogPath now points at a different inode than
+// file (the typical logrotate create-and-swap), or whether file has been
+// truncated in place to something shorter than offset.
+func (t *Tailer) rotated(file *os.File, offset int64) (bool, error) {
+	curInfo, err := file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("filesource: stat open file: %w", err)
+	}
+	pathInfo, err := os.Stat(t.LogPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("filesource: stat %s: %w", t.LogPath, err)
+	}
+	if inodeOf(curInfo) != inodeOf(pathInfo) {
+		return true, nil
+	}
+	return pathInfo.Size() < offset, nil
+}
+
+func (t *Tailer) openForInode(wantInode uint64, offset *int64) (*os.File, error) {
+	file, err := os.Open(t.LogPath)
+	if err != nil {
+		return nil, fmt.Errorf("filesource: open %s: %w", t.LogPath, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("filesource: stat %s: %w", t.LogPath, err)
+	}
+
+	if wantInode != 0 && inodeOf(info) == wantInode {
+		if _, err := file.Seek(*offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("filesource: seek %s: %w", t.LogPath, err)
+		}
+	} else {
+		*offset = 0
+	}
+	return file, nil
+}
+
+func fileInfoOrNil(file *os.File) os.FileInfo {
+	info, err := file.Stat()
+	if err != nil {
+		return nil
+	}
+	return info
+}
+
+type checkpointFile struct {
+	Offset int64  `json:"offset"`
+	Inode  uint64 `json:"inode"`
+}
+
+func (t *Tailer) loadCheckpoint() (offset int64, inode uint64, err error) {
+	data, err := os.ReadFile(t.CheckpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("filesource: read checkpoint %s: %w", t.CheckpointPath, err)
+	}
+	var cp checkpointFile
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return 0, 0, fmt.Errorf("filesource: decode checkpoint %s: %w", t.CheckpointPath, err)
+	}
+	return cp.Offset, cp.Inode, nil
+}
+
+func (t *Tailer) saveCheckpoint(offset int64, inode uint64) error {
+	data, err := json.Marshal(checkpointFile{Offset: offset, Inode: inode})
+	if err != nil {
+		return err
+	}
+	tmp := t.CheckpointPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("filesource: write checkpoint: %w", err)
+	}
+	return os.Rename(tmp, t.CheckpointPath)
+}
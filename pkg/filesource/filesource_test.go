@@ -0,0 +1,69 @@
+package filesource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// TestTailer_PartialLineNotLostOnEOF reproduces polling an audit log while
+// the writer's trailing newline hasn't landed yet: the tailer must wait for
+// the rest of the line instead of treating the partial bytes as a complete
+// (and therefore truncated, undecodable) record.
+func TestTailer_PartialLineNotLostOnEOF(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "audit.log")
+	checkpointPath := filepath.Join(dir, "audit.log.checkpoint")
+
+	const partial = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","auditID":"` + "deadbeef"
+	const rest = `","stage":"ResponseComplete","verb":"get"}` + "\n"
+
+	if err := os.WriteFile(logPath, []byte(partial), 0o600); err != nil {
+		t.Fatalf("write partial line: %v", err)
+	}
+
+	tailer := NewTailer(logPath, checkpointPath, 20*time.Millisecond)
+
+	events := make(chan *auditapi.Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- tailer.Run(ctx, func(_ context.Context, event *auditapi.Event) error {
+			events <- event
+			return nil
+		})
+	}()
+
+	// Give the tailer a couple of poll cycles to observe the partial line
+	// at EOF before the rest of it is appended.
+	time.Sleep(60 * time.Millisecond)
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("open for append: %v", err)
+	}
+	if _, err := f.WriteString(rest); err != nil {
+		t.Fatalf("write rest of line: %v", err)
+	}
+	f.Close()
+
+	select {
+	case event := <-events:
+		if event.AuditID != "deadbeef" {
+			t.Fatalf("auditID = %q, want %q", event.AuditID, "deadbeef")
+		}
+	case err := <-runErr:
+		t.Fatalf("tailer.Run returned early: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the completed line to be delivered as one event")
+	}
+
+	cancel()
+	<-runErr
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package filesource
+
+import "os"
+
+// inodeOf has no portable equivalent outside unix-like filesystems, so
+// rotation detection on such platforms falls back to the truncation check
+// in (*Tailer).rotated.
+func inodeOf(os.FileInfo) uint64 {
+	return 0
+}
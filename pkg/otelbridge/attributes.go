@@ -0,0 +1,118 @@
+package otelbridge
+
+import (
+	"log/slog"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/hmsayem/k8s-audit-otel-logs/pkg/auditfilter"
+)
+
+// Attribute keys follow a "k8s.audit.*" convention modeled on OpenTelemetry
+// semantic conventions, so they stay stable and self-describing regardless
+// of which exporter ends up receiving them.
+const (
+	attrLevel                    = "k8s.audit.level"
+	attrAuditID                  = "k8s.audit.audit_id"
+	attrStage                    = "k8s.audit.stage"
+	attrRequestURI               = "k8s.audit.request_uri"
+	attrVerb                     = "k8s.audit.verb"
+	attrSourceIPs                = "k8s.audit.source_ips"
+	attrUserAgent                = "k8s.audit.user_agent"
+	attrRequestReceivedTimestamp = "k8s.audit.request_received_timestamp"
+	attrStageTimestamp           = "k8s.audit.stage_timestamp"
+	attrUser                     = "k8s.audit.user"
+	attrImpersonatedUser         = "k8s.audit.impersonated_user"
+	attrObjectRef                = "k8s.audit.object_ref"
+	attrResponseStatus           = "k8s.audit.response_status"
+	attrRequestObject            = "k8s.audit.request_object"
+	attrResponseObject           = "k8s.audit.response_object"
+)
+
+// AttributesFromAuditEvent flattens a Kubernetes audit event into slog
+// attributes suitable for emitting through the OTel log bridge. If filter
+// is non-nil, the event is redacted in place before any attributes are
+// read from it, so filtering only ever happens once per event regardless
+// of how many exporters are registered.
+//
+// ObjectRef, ResponseStatus, ImpersonatedUser, RequestObject, and
+// ResponseObject are all optional per the audit v1 API and are only added
+// when present; Groups and SourceIPs are emitted as real string slices
+// rather than joined strings, and timestamps are RFC3339 strings.
+func AttributesFromAuditEvent(event *auditapi.Event, filter *auditfilter.Filter) []slog.Attr {
+	if filter != nil {
+		if err := filter.Apply(event); err != nil {
+			slog.Error("auditfilter: failed to redact audit event", "auditID", event.AuditID, "err", err)
+		}
+	}
+
+	attrs := []slog.Attr{
+		slog.String(attrLevel, string(event.Level)),
+		slog.String(attrAuditID, string(event.AuditID)),
+		slog.String(attrStage, string(event.Stage)),
+		slog.String(attrRequestURI, event.RequestURI),
+		slog.String(attrVerb, event.Verb),
+		slog.Any(attrSourceIPs, event.SourceIPs),
+		slog.String(attrUserAgent, event.UserAgent),
+		slog.Group(attrUser, userAttrs(event.User)...),
+		slog.String(attrRequestReceivedTimestamp, formatMicroTime(event.RequestReceivedTimestamp)),
+		slog.String(attrStageTimestamp, formatMicroTime(event.StageTimestamp)),
+	}
+
+	if event.ImpersonatedUser != nil {
+		attrs = append(attrs, slog.Group(attrImpersonatedUser, userAttrs(*event.ImpersonatedUser)...))
+	}
+	if event.ObjectRef != nil {
+		attrs = append(attrs, slog.Group(attrObjectRef, objectRefAttrs(event.ObjectRef)...))
+	}
+	if event.ResponseStatus != nil {
+		attrs = append(attrs, slog.Group(attrResponseStatus, responseStatusAttrs(event.ResponseStatus)...))
+	}
+	if event.RequestObject != nil {
+		attrs = append(attrs, slog.String(attrRequestObject, string(event.RequestObject.Raw)))
+	}
+	if event.ResponseObject != nil {
+		attrs = append(attrs, slog.String(attrResponseObject, string(event.ResponseObject.Raw)))
+	}
+
+	return attrs
+}
+
+func userAttrs(user authnv1.UserInfo) []any {
+	return []any{
+		slog.String("username", user.Username),
+		slog.String("uid", user.UID),
+		slog.Any("groups", user.Groups),
+	}
+}
+
+func objectRefAttrs(ref *auditapi.ObjectReference) []any {
+	return []any{
+		slog.String("uid", string(ref.UID)),
+		slog.String("resource", ref.Resource),
+		slog.String("name", ref.Name),
+		slog.String("namespace", ref.Namespace),
+		slog.String("api_group", ref.APIGroup),
+		slog.String("api_version", ref.APIVersion),
+		slog.String("resource_version", ref.ResourceVersion),
+	}
+}
+
+func responseStatusAttrs(status *metav1.Status) []any {
+	return []any{
+		slog.String("status", status.Status),
+		slog.String("message", status.Message),
+		slog.String("reason", string(status.Reason)),
+		slog.Int("code", int(status.Code)),
+	}
+}
+
+func formatMicroTime(t metav1.MicroTime) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339Nano)
+}
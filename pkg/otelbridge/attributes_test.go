@@ -0,0 +1,126 @@
+package otelbridge
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func findAttr(attrs []slog.Attr, key string) (slog.Attr, bool) {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a, true
+		}
+	}
+	return slog.Attr{}, false
+}
+
+func TestAttributesFromAuditEvent_Metadata(t *testing.T) {
+	now := metav1.NewMicroTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	event := &auditapi.Event{
+		Level:                    auditapi.LevelMetadata,
+		AuditID:                  "abc-123",
+		Stage:                    auditapi.StageResponseComplete,
+		RequestURI:               "/api/v1/pods",
+		Verb:                     "get",
+		User:                     authnv1.UserInfo{Username: "alice", Groups: []string{"system:authenticated"}},
+		RequestReceivedTimestamp: now,
+		StageTimestamp:           now,
+	}
+
+	attrs := AttributesFromAuditEvent(event, nil)
+
+	if a, ok := findAttr(attrs, attrAuditID); !ok || a.Value.String() != "abc-123" {
+		t.Fatalf("audit_id = %v, ok=%v", a, ok)
+	}
+	if a, ok := findAttr(attrs, attrUser); !ok {
+		t.Fatal("expected user group attribute")
+	} else if got := a.Value.Group(); len(got) != 3 {
+		t.Fatalf("user group has %d attrs, want 3", len(got))
+	}
+
+	// Metadata level carries no object ref, response status, or bodies.
+	for _, key := range []string{attrObjectRef, attrResponseStatus, attrImpersonatedUser, attrRequestObject, attrResponseObject} {
+		if _, ok := findAttr(attrs, key); ok {
+			t.Errorf("unexpected attribute %q at Metadata level", key)
+		}
+	}
+}
+
+func TestAttributesFromAuditEvent_Request(t *testing.T) {
+	event := &auditapi.Event{
+		Level: auditapi.LevelRequest,
+		User:  authnv1.UserInfo{Username: "bob"},
+		ObjectRef: &auditapi.ObjectReference{
+			Resource:  "pods",
+			Namespace: "default",
+			Name:      "my-pod",
+		},
+		RequestObject: &runtime.Unknown{Raw: []byte(`{"kind":"Pod"}`)},
+	}
+
+	attrs := AttributesFromAuditEvent(event, nil)
+
+	a, ok := findAttr(attrs, attrObjectRef)
+	if !ok {
+		t.Fatal("expected object_ref attribute")
+	}
+	refAttrs := a.Value.Group()
+	if name, ok := findAttr(refAttrs, "name"); !ok || name.Value.String() != "my-pod" {
+		t.Fatalf("object_ref.name = %v, ok=%v", name, ok)
+	}
+
+	if a, ok := findAttr(attrs, attrRequestObject); !ok || a.Value.String() != `{"kind":"Pod"}` {
+		t.Fatalf("request_object = %v, ok=%v", a, ok)
+	}
+	if _, ok := findAttr(attrs, attrResponseObject); ok {
+		t.Error("unexpected response_object attribute at Request level")
+	}
+}
+
+func TestAttributesFromAuditEvent_RequestResponse(t *testing.T) {
+	event := &auditapi.Event{
+		Level:            auditapi.LevelRequestResponse,
+		User:             authnv1.UserInfo{Username: "carol"},
+		ImpersonatedUser: &authnv1.UserInfo{Username: "dave"},
+		ObjectRef:        &auditapi.ObjectReference{Resource: "pods"},
+		ResponseStatus:   &metav1.Status{Status: "Success", Code: 200},
+		RequestObject:    &runtime.Unknown{Raw: []byte(`{"kind":"Pod"}`)},
+		ResponseObject:   &runtime.Unknown{Raw: []byte(`{"kind":"Pod","status":"ok"}`)},
+	}
+
+	attrs := AttributesFromAuditEvent(event, nil)
+
+	if a, ok := findAttr(attrs, attrResponseObject); !ok || a.Value.String() != `{"kind":"Pod","status":"ok"}` {
+		t.Fatalf("response_object = %v, ok=%v", a, ok)
+	}
+	if a, ok := findAttr(attrs, attrImpersonatedUser); !ok {
+		t.Fatal("expected impersonated_user attribute")
+	} else if username, ok := findAttr(a.Value.Group(), "username"); !ok || username.Value.String() != "dave" {
+		t.Fatalf("impersonated_user.username = %v, ok=%v", username, ok)
+	}
+	if a, ok := findAttr(attrs, attrResponseStatus); !ok {
+		t.Fatal("expected response_status attribute")
+	} else if code, ok := findAttr(a.Value.Group(), "code"); !ok || code.Value.Int64() != 200 {
+		t.Fatalf("response_status.code = %v, ok=%v", code, ok)
+	}
+}
+
+// A nil ObjectRef/ResponseStatus/RequestObject/ResponseObject must never panic.
+func TestAttributesFromAuditEvent_NilOptionalFields(t *testing.T) {
+	event := &auditapi.Event{
+		Level: auditapi.LevelMetadata,
+		User:  authnv1.UserInfo{Username: "eve"},
+	}
+
+	attrs := AttributesFromAuditEvent(event, nil)
+
+	if len(attrs) == 0 {
+		t.Fatal("expected non-empty attributes for a minimal event")
+	}
+}
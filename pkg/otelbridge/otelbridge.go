@@ -0,0 +1,38 @@
+// Package otelbridge wires Kubernetes audit events into the OpenTelemetry
+// logs SDK: it builds the resource/logger provider and converts an audit
+// event into a set of slog attributes.
+package otelbridge
+
+import (
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"log/slog"
+)
+
+// NewResource builds the OTel resource describing this service.
+func NewResource() (*resource.Resource, error) {
+	return resource.Merge(resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("test-service"),
+			semconv.ServiceVersion("0.1.0"),
+		))
+}
+
+// NewLoggerProvider builds a LoggerProvider registered with the given
+// processors. Registering more than one processor (see pkg/exporter)
+// fans every emitted audit event out to each destination simultaneously.
+func NewLoggerProvider(res *resource.Resource, processors ...log.Processor) (*log.LoggerProvider, error) {
+	opts := []log.LoggerProviderOption{log.WithResource(res)}
+	for _, p := range processors {
+		opts = append(opts, log.WithProcessor(p))
+	}
+	return log.NewLoggerProvider(opts...), nil
+}
+
+// NewLogHandler returns an slog.Handler backed by the given LoggerProvider.
+func NewLogHandler(loggerProvider *log.LoggerProvider) slog.Handler {
+	return otelslog.NewHandler("test", otelslog.WithLoggerProvider(loggerProvider))
+}
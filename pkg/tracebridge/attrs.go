@@ -0,0 +1,50 @@
+package tracebridge
+
+import (
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ConvertAttrs flattens slog attributes (as produced by
+// otelbridge.AttributesFromAuditEvent) into OTel span attributes. Nested
+// groups are flattened with a dot-joined key, since span attributes have
+// no native notion of nesting.
+func ConvertAttrs(attrs []slog.Attr) []attribute.KeyValue {
+	var kvs []attribute.KeyValue
+	for _, a := range attrs {
+		kvs = append(kvs, convertAttr("", a)...)
+	}
+	return kvs
+}
+
+func convertAttr(prefix string, a slog.Attr) []attribute.KeyValue {
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		var kvs []attribute.KeyValue
+		for _, ga := range v.Group() {
+			kvs = append(kvs, convertAttr(key, ga)...)
+		}
+		return kvs
+	case slog.KindString:
+		return []attribute.KeyValue{attribute.String(key, v.String())}
+	case slog.KindInt64:
+		return []attribute.KeyValue{attribute.Int64(key, v.Int64())}
+	case slog.KindBool:
+		return []attribute.KeyValue{attribute.Bool(key, v.Bool())}
+	case slog.KindFloat64:
+		return []attribute.KeyValue{attribute.Float64(key, v.Float64())}
+	default:
+		if ss, ok := v.Any().([]string); ok {
+			return []attribute.KeyValue{attribute.StringSlice(key, ss)}
+		}
+		return []attribute.KeyValue{attribute.String(key, fmt.Sprint(v.Any()))}
+	}
+}
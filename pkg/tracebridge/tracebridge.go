@@ -0,0 +1,100 @@
+// Package tracebridge correlates Kubernetes audit events into OpenTelemetry
+// traces. Each event becomes a span seeded from its AuditID, so the
+// RequestReceived, ResponseStarted, ResponseComplete, and Panic stages of
+// the same API request all land on the same trace as sibling spans,
+// letting operators see the full timeline of a request in any
+// OTLP-compatible tracing backend. It is an opt-in companion to the log
+// pipeline: the log record for each stage still gets its usual attributes,
+// plus the trace_id/span_id of the span emitted here.
+package tracebridge
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"log/slog"
+
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// Bridge emits a span per audit event, correlated by AuditID.
+type Bridge struct {
+	tracer oteltrace.Tracer
+}
+
+// New returns a Bridge that starts spans on the given TracerProvider.
+func New(tp *sdktrace.TracerProvider) *Bridge {
+	return &Bridge{tracer: tp.Tracer("github.com/hmsayem/k8s-audit-otel-logs/pkg/tracebridge")}
+}
+
+// Correlate emits a span for one stage of event, spanning
+// RequestReceivedTimestamp to StageTimestamp with attrs attached, and
+// returns the trace_id/span_id as slog attributes to attach to the
+// matching log record. Every event sharing the same AuditID produces a
+// span on the same trace, regardless of which process or goroutine
+// observes it.
+func (b *Bridge) Correlate(ctx context.Context, event *auditapi.Event, attrs []attribute.KeyValue) []slog.Attr {
+	parentCtx := oteltrace.ContextWithSpanContext(ctx, parentSpanContext(string(event.AuditID)))
+
+	start := event.RequestReceivedTimestamp.Time
+	end := event.StageTimestamp.Time
+	if end.Before(start) {
+		end = start
+	}
+
+	_, span := b.tracer.Start(parentCtx, spanName(event),
+		oteltrace.WithTimestamp(start),
+		oteltrace.WithAttributes(attrs...),
+	)
+	span.End(oteltrace.WithTimestamp(end))
+
+	sc := span.SpanContext()
+	return []slog.Attr{
+		slog.String("trace_id", sc.TraceID().String()),
+		slog.String("span_id", sc.SpanID().String()),
+	}
+}
+
+func spanName(event *auditapi.Event) string {
+	if event.RequestURI == "" {
+		return string(event.Stage)
+	}
+	return event.Verb + " " + event.RequestURI
+}
+
+// parentSpanContext deterministically derives a (TraceID, SpanID) pair from
+// auditID. Using it as a remote parent for every stage's span is what
+// gives all of them the same TraceID without any out-of-band
+// coordination between stages.
+func parentSpanContext(auditID string) oteltrace.SpanContext {
+	sum := sha256.Sum256([]byte(auditID))
+	var traceID oteltrace.TraceID
+	var spanID oteltrace.SpanID
+	copy(traceID[:], sum[:16])
+	copy(spanID[:], sum[16:24])
+
+	return oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: oteltrace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+// NewTracerProvider builds a TracerProvider for the correlated spans. With
+// no endpoint configured it exports to stdout, matching the log
+// pipeline's own no-config default.
+func NewTracerProvider(res *resource.Resource, endpoint string, insecure bool) (*sdktrace.TracerProvider, error) {
+	exp, err := newSpanExporter(endpoint, insecure)
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exp),
+	), nil
+}
@@ -0,0 +1,111 @@
+package tracebridge
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	auditapi "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func findAttr(attrs []slog.Attr, key string) (slog.Attr, bool) {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a, true
+		}
+	}
+	return slog.Attr{}, false
+}
+
+func newTestBridge(t *testing.T) *Bridge {
+	t.Helper()
+	exp, err := stdouttrace.New(stdouttrace.WithWriter(io.Discard))
+	if err != nil {
+		t.Fatalf("stdouttrace.New: %v", err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	t.Cleanup(func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			t.Errorf("tracer provider shutdown: %v", err)
+		}
+	})
+	return New(tp)
+}
+
+func TestBridge_CorrelateSameAuditIDSharesTraceID(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	received := &auditapi.Event{AuditID: "abc-123", Stage: auditapi.StageRequestReceived}
+	complete := &auditapi.Event{AuditID: "abc-123", Stage: auditapi.StageResponseComplete}
+
+	attrsA := bridge.Correlate(context.Background(), received, nil)
+	attrsB := bridge.Correlate(context.Background(), complete, nil)
+
+	traceA, ok := findAttr(attrsA, "trace_id")
+	if !ok {
+		t.Fatal("missing trace_id attribute")
+	}
+	traceB, ok := findAttr(attrsB, "trace_id")
+	if !ok {
+		t.Fatal("missing trace_id attribute")
+	}
+	if traceA.Value.String() != traceB.Value.String() {
+		t.Errorf("trace_id = %q and %q, want equal for the same AuditID", traceA.Value.String(), traceB.Value.String())
+	}
+
+	spanA, ok := findAttr(attrsA, "span_id")
+	if !ok {
+		t.Fatal("missing span_id attribute")
+	}
+	spanB, ok := findAttr(attrsB, "span_id")
+	if !ok {
+		t.Fatal("missing span_id attribute")
+	}
+	if spanA.Value.String() == spanB.Value.String() {
+		t.Errorf("span_id = %q for both stages, want distinct spans on the shared trace", spanA.Value.String())
+	}
+}
+
+func TestBridge_CorrelateDifferentAuditIDDifferentTraceID(t *testing.T) {
+	bridge := newTestBridge(t)
+
+	eventA := &auditapi.Event{AuditID: "abc-123", Stage: auditapi.StageResponseComplete}
+	eventB := &auditapi.Event{AuditID: "xyz-789", Stage: auditapi.StageResponseComplete}
+
+	attrsA := bridge.Correlate(context.Background(), eventA, nil)
+	attrsB := bridge.Correlate(context.Background(), eventB, nil)
+
+	traceA, _ := findAttr(attrsA, "trace_id")
+	traceB, _ := findAttr(attrsB, "trace_id")
+	if traceA.Value.String() == traceB.Value.String() {
+		t.Errorf("trace_id = %q for both AuditIDs, want distinct traces", traceA.Value.String())
+	}
+}
+
+func TestConvertAttrs_FlattensGroups(t *testing.T) {
+	attrs := []slog.Attr{
+		slog.Group("user", slog.String("name", "alice"), slog.Int("uid", 7)),
+		slog.String("top", "v"),
+	}
+
+	kvs := ConvertAttrs(attrs)
+
+	got := make(map[string]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		got[string(kv.Key)] = kv.Value
+	}
+
+	if v, ok := got["user.name"]; !ok || v.AsString() != "alice" {
+		t.Errorf("user.name = %v, ok=%v, want \"alice\"", v, ok)
+	}
+	if v, ok := got["user.uid"]; !ok || v.AsInt64() != 7 {
+		t.Errorf("user.uid = %v, ok=%v, want 7", v, ok)
+	}
+	if v, ok := got["top"]; !ok || v.AsString() != "v" {
+		t.Errorf("top = %v, ok=%v, want \"v\"", v, ok)
+	}
+}